@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pivotal-cf/pivnet-cli/rc"
+)
+
+// apiPrefix is prepended to every Pivotal Network API path.
+const apiPrefix = "/api/v2"
+
+// currentProfile loads the profile selected by --profile from
+// `.pivnetrc`, resolving its credentials out of the OS keyring first if
+// that's where they're stored.
+func currentProfile() (rc.Profile, error) {
+	rcContents, err := rc.LoadRC(Pivnet.ConfigFilepath)
+	if err != nil {
+		return rc.Profile{}, err
+	}
+
+	profile := rcContents.ProfileForName(Pivnet.ProfileName)
+	if profile == nil {
+		return rc.Profile{}, fmt.Errorf("%s; run `pivnet login` first", rc.ErrProfileNotFound(Pivnet.ProfileName))
+	}
+
+	return resolveCredential(*profile)
+}
+
+// httpClientForProfile returns an *http.Client that authenticates every
+// request as profile.
+func httpClientForProfile(profile rc.Profile) *http.Client {
+	switch profile.CredentialType {
+	case rc.CredentialTypeOIDC:
+		return &http.Client{Transport: &oidcTransport{profile: profile}}
+	default:
+		return &http.Client{Transport: &apiTokenTransport{apiToken: profile.APIToken}}
+	}
+}
+
+// requireAuthentication hits host's /authentication endpoint to confirm
+// client's credentials are still valid, the same check every other
+// command performs before doing anything else.
+func requireAuthentication(client *http.Client, host string) error {
+	resp, err := client.Get(host + apiPrefix + "/authentication")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("not logged in; run `pivnet login` first")
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+// Package commands implements the pivnet CLI's subcommands and the
+// global options shared by all of them.
+package commands
+
+// PivnetCommand holds the global flags available to every subcommand,
+// plus the subcommands themselves. main.go parses into a single
+// instance of this struct and also stores it in the package-level
+// Pivnet variable so subcommand Execute methods can read global state
+// such as the configured profile and config file location.
+type PivnetCommand struct {
+	Version bool `short:"v" long:"version" description:"Print the version of pivnet and exit"`
+
+	VerboseOutput bool `long:"verbose" description:"Display verbose output"`
+
+	Format   string `long:"format" description:"Format to print as" default:"table" choice:"table" choice:"json" choice:"yaml" choice:"csv" choice:"tsv" choice:"template"`
+	Fields   string `long:"fields" description:"Comma-separated list of fields to include, in order, for --format=table/csv/tsv, e.g. slug,name,id"`
+	Template string `long:"template" description:"Go text/template string to render each result with, for --format=template"`
+
+	Host              string `long:"host" description:"Pivotal Network host"`
+	ConfigFilepath    string `long:"config" description:"Path to config file" default:".pivnetrc"`
+	ProfileName       string `long:"profile" description:"Name of profile to use from config file" default:"default"`
+	SkipSSLValidation bool   `long:"skip-ssl-validation" description:"Skip SSL validation of API endpoint"`
+
+	Login              LoginCommand              `command:"login" description:"Logs in by authenticating with Pivotal Network"`
+	Logout             LogoutCommand             `command:"logout" description:"Logs out by removing local credentials"`
+	MigrateCredentials MigrateCredentialsCommand `command:"migrate-credentials" description:"Migrates the current profile's credentials between credential stores"`
+	Product            ProductCommand            `command:"product" description:"Shows a single product"`
+}
+
+// Pivnet is the parsed set of global options for the running process,
+// populated by main.go before any subcommand's Execute is invoked.
+var Pivnet PivnetCommand
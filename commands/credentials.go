@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"encoding/json"
+
+	"github.com/pivotal-cf/pivnet-cli/rc"
+)
+
+// oidcSecret is the JSON blob stored as a single keyring secret for an
+// OIDC profile, since the keyring only stores one string per account.
+type oidcSecret struct {
+	AccessToken       string `json:"access_token"`
+	RefreshToken      string `json:"refresh_token"`
+	IDToken           string `json:"id_token"`
+	AccessTokenExpiry int64  `json:"access_token_expiry"`
+}
+
+func encodeOIDCSecret(profile rc.Profile) string {
+	b, _ := json.Marshal(oidcSecret{
+		AccessToken:       profile.AccessToken,
+		RefreshToken:      profile.RefreshToken,
+		IDToken:           profile.IDToken,
+		AccessTokenExpiry: profile.AccessTokenExpiry,
+	})
+	return string(b)
+}
+
+func decodeOIDCSecret(secret string) (rc.Profile, error) {
+	var s oidcSecret
+	if err := json.Unmarshal([]byte(secret), &s); err != nil {
+		return rc.Profile{}, err
+	}
+
+	return rc.Profile{
+		AccessToken:       s.AccessToken,
+		RefreshToken:      s.RefreshToken,
+		IDToken:           s.IDToken,
+		AccessTokenExpiry: s.AccessTokenExpiry,
+	}, nil
+}
+
+// moveToKeyring writes secret to the credential store under profile's
+// keyring account and returns a copy of profile with its secret fields
+// cleared and its reference fields populated, ready to be written to
+// `.pivnetrc` in plaintext.
+func moveToKeyring(profile rc.Profile, secret string) (rc.Profile, error) {
+	service := keyringServiceName
+	account := keyringAccount(profile.Name, profile.Host)
+
+	if err := credentialStore().Set(service, account, secret); err != nil {
+		return rc.Profile{}, err
+	}
+
+	underlying := profile.CredentialType
+
+	return rc.Profile{
+		Name:                     profile.Name,
+		Host:                     profile.Host,
+		SkipSSLValidation:        profile.SkipSSLValidation,
+		CredentialType:           rc.CredentialTypeKeyring,
+		UnderlyingCredentialType: underlying,
+		// OIDCIssuer isn't secret, so it's kept in plaintext on the
+		// keyring-stub profile, the same way Host is, rather than in the
+		// keyring secret; resolveCredential restores it onto the
+		// resolved profile below.
+		OIDCIssuer:     profile.OIDCIssuer,
+		KeyringService: service,
+		KeyringAccount: account,
+	}, nil
+}
+
+// resolveCredential returns profile with its secret fields populated
+// in-memory, fetching them from the credential store first if profile's
+// CredentialType is rc.CredentialTypeKeyring. It never persists
+// anything; it's used just before a profile's credentials are needed to
+// make an API call.
+func resolveCredential(profile rc.Profile) (rc.Profile, error) {
+	if profile.CredentialType != rc.CredentialTypeKeyring {
+		return profile, nil
+	}
+
+	secret, err := credentialStore().Get(profile.KeyringService, profile.KeyringAccount)
+	if err != nil {
+		return rc.Profile{}, err
+	}
+
+	switch profile.UnderlyingCredentialType {
+	case rc.CredentialTypeOIDC:
+		resolved, err := decodeOIDCSecret(secret)
+		if err != nil {
+			return rc.Profile{}, err
+		}
+		resolved.Name = profile.Name
+		resolved.Host = profile.Host
+		resolved.CredentialType = rc.CredentialTypeOIDC
+		resolved.OIDCIssuer = profile.OIDCIssuer
+		// Keep the keyring reference around so a later token refresh
+		// knows to write the new tokens back to the keyring rather than
+		// to `.pivnetrc`.
+		resolved.KeyringService = profile.KeyringService
+		resolved.KeyringAccount = profile.KeyringAccount
+		return resolved, nil
+	default:
+		profile.APIToken = secret
+		profile.CredentialType = rc.CredentialTypeAPIToken
+		return profile, nil
+	}
+}
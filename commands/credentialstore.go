@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/pivotal-cf/pivnet-cli/credentials"
+)
+
+// fakeKeyringPathEnvVar, when set, swaps the OS keyring for a
+// file-backed fake; it is only ever set by the integration test suite.
+const fakeKeyringPathEnvVar = "PIVNET_CLI_FAKE_KEYRING_PATH"
+
+// keyringServiceName identifies pivnet-cli's entries among whatever
+// else is stored in the user's keyring.
+const keyringServiceName = "pivnet-cli"
+
+// credentialStore returns the Store that login/logout/migrate-credentials
+// use for --credential-store=keyring.
+func credentialStore() credentials.Store {
+	if path := os.Getenv(fakeKeyringPathEnvVar); path != "" {
+		return credentials.FileFake{Path: path}
+	}
+	return credentials.OSKeyring{}
+}
+
+// keyringAccount is the account name a profile's secret is filed under:
+// unique per host/profile so multiple profiles don't collide.
+func keyringAccount(profileName, host string) string {
+	return profileName + "@" + host
+}
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pivotal-cf/pivnet-cli/login"
+	"github.com/pivotal-cf/pivnet-cli/rc"
+)
+
+// OIDCClientID is the client id pivnet's CLI is registered under with
+// its OIDC providers. It is not a secret: the authorization-code flow
+// with PKCE does not require one.
+const OIDCClientID = "pivnet-cli"
+
+// LoginCommand logs the user in, either via a static `--api-token` or
+// via the browser-based `--sso`/`--oidc-issuer` flow, and persists the
+// resulting profile to `.pivnetrc`.
+type LoginCommand struct {
+	APIToken        string `long:"api-token" description:"API token to authenticate with"`
+	Host            string `long:"host" description:"Host to authenticate against" required:"true"`
+	SSO             bool   `long:"sso" description:"Authenticate via the browser-based OIDC login flow instead of --api-token"`
+	OIDCIssuer      string `long:"oidc-issuer" description:"OIDC issuer URL to discover endpoints from; required with --sso"`
+	CredentialStore string `long:"credential-store" description:"Where to persist the resulting credential" default:"config" choice:"config" choice:"keyring"`
+}
+
+// Execute authenticates the user and writes a profile to the configured
+// `.pivnetrc`.
+func (command *LoginCommand) Execute([]string) error {
+	switch {
+	case command.SSO:
+		return command.executeSSO()
+	case command.APIToken != "":
+		return command.executeAPIToken()
+	default:
+		return errors.New("either --api-token or --sso must be provided")
+	}
+}
+
+func (command *LoginCommand) executeAPIToken() error {
+	profile := rc.Profile{
+		Name:           Pivnet.ProfileName,
+		Host:           command.Host,
+		APIToken:       command.APIToken,
+		CredentialType: rc.CredentialTypeAPIToken,
+	}
+
+	if err := requireAuthentication(httpClientForProfile(profile), profile.Host); err != nil {
+		return fmt.Errorf("validating API token: %s", err)
+	}
+
+	if command.CredentialStore == "keyring" {
+		var err error
+		profile, err = moveToKeyring(profile, command.APIToken)
+		if err != nil {
+			return fmt.Errorf("storing credential in keyring: %s", err)
+		}
+	}
+
+	return saveProfile(profile)
+}
+
+func (command *LoginCommand) executeSSO() error {
+	if command.OIDCIssuer == "" {
+		return errors.New("--oidc-issuer must be provided with --sso")
+	}
+
+	flow := login.Flow{
+		Issuer:   command.OIDCIssuer,
+		ClientID: OIDCClientID,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	result, err := flow.Run(ctx)
+	if err != nil {
+		return fmt.Errorf("OIDC login failed: %s", err)
+	}
+
+	profile := rc.Profile{
+		Name:              Pivnet.ProfileName,
+		Host:              command.Host,
+		CredentialType:    rc.CredentialTypeOIDC,
+		OIDCIssuer:        result.Issuer,
+		AccessToken:       result.AccessToken,
+		RefreshToken:      result.RefreshToken,
+		IDToken:           result.IDToken,
+		AccessTokenExpiry: result.ExpiresAt.Unix(),
+	}
+
+	if err := requireAuthentication(httpClientForProfile(profile), profile.Host); err != nil {
+		return fmt.Errorf("validating OIDC credentials: %s", err)
+	}
+
+	if command.CredentialStore == "keyring" {
+		var err error
+		profile, err = moveToKeyring(profile, encodeOIDCSecret(profile))
+		if err != nil {
+			return fmt.Errorf("storing credential in keyring: %s", err)
+		}
+	}
+
+	return saveProfile(profile)
+}
+
+func saveProfile(profile rc.Profile) error {
+	rcContents, err := rc.LoadRC(Pivnet.ConfigFilepath)
+	if err != nil {
+		return err
+	}
+
+	rcContents.UpsertProfile(profile)
+
+	return rc.SaveRC(Pivnet.ConfigFilepath, rcContents)
+}
@@ -0,0 +1,28 @@
+package commands
+
+import "github.com/pivotal-cf/pivnet-cli/rc"
+
+// LogoutCommand removes the active profile's credentials from
+// `.pivnetrc`.
+type LogoutCommand struct{}
+
+// Execute removes the current profile from the config file, deleting
+// its keyring entry first if its credentials were stored there.
+func (command *LogoutCommand) Execute([]string) error {
+	rcContents, err := rc.LoadRC(Pivnet.ConfigFilepath)
+	if err != nil {
+		return err
+	}
+
+	if profile := rcContents.ProfileForName(Pivnet.ProfileName); profile != nil {
+		if profile.CredentialType == rc.CredentialTypeKeyring {
+			if err := credentialStore().Delete(profile.KeyringService, profile.KeyringAccount); err != nil {
+				return err
+			}
+		}
+	}
+
+	rcContents.RemoveProfile(Pivnet.ProfileName)
+
+	return rc.SaveRC(Pivnet.ConfigFilepath, rcContents)
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pivotal-cf/pivnet-cli/rc"
+)
+
+// MigrateCredentialsCommand moves the current profile's credentials
+// between credential stores, e.g. out of plaintext `.pivnetrc` and into
+// the OS keyring.
+type MigrateCredentialsCommand struct {
+	To string `long:"to" description:"Credential store to migrate the current profile to" required:"true" choice:"keyring"`
+}
+
+// Execute migrates the current profile's credentials to command.To.
+func (command *MigrateCredentialsCommand) Execute([]string) error {
+	if command.To != "keyring" {
+		return fmt.Errorf("unsupported --to value %q", command.To)
+	}
+
+	rcContents, err := rc.LoadRC(Pivnet.ConfigFilepath)
+	if err != nil {
+		return err
+	}
+
+	profile := rcContents.ProfileForName(Pivnet.ProfileName)
+	if profile == nil {
+		return rc.ErrProfileNotFound(Pivnet.ProfileName)
+	}
+
+	if profile.CredentialType == rc.CredentialTypeKeyring {
+		return nil
+	}
+
+	var secret string
+	switch profile.CredentialType {
+	case rc.CredentialTypeOIDC:
+		secret = encodeOIDCSecret(*profile)
+	default:
+		secret = profile.APIToken
+	}
+
+	migrated, err := moveToKeyring(*profile, secret)
+	if err != nil {
+		return fmt.Errorf("migrating credentials to keyring: %s", err)
+	}
+
+	rcContents.UpsertProfile(migrated)
+
+	return rc.SaveRC(Pivnet.ConfigFilepath, rcContents)
+}
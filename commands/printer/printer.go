@@ -0,0 +1,293 @@
+// Package printer renders command results in the CLI's --format=json,
+// yaml, csv, tsv and template output formats.
+package printer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Format is one of the values accepted by --format.
+type Format string
+
+const (
+	FormatTable    Format = "table"
+	FormatJSON     Format = "json"
+	FormatYAML     Format = "yaml"
+	FormatCSV      Format = "csv"
+	FormatTSV      Format = "tsv"
+	FormatTemplate Format = "template"
+)
+
+// Printer renders a single result or slice of results to OutWriter in
+// the configured Format.
+type Printer struct {
+	OutWriter io.Writer
+
+	Format   Format
+	Fields   string
+	Template string
+}
+
+// NewPrinter returns a Printer that writes to w.
+func NewPrinter(w io.Writer) Printer {
+	return Printer{OutWriter: w}
+}
+
+// PrintData writes data - a struct or a slice of structs - to
+// OutWriter according to the configured Format.
+func (p Printer) PrintData(data interface{}) error {
+	switch p.Format {
+	case FormatTable:
+		return p.printTable(data)
+	case FormatJSON:
+		return p.printJSON(data)
+	case FormatYAML:
+		return p.printYAML(data)
+	case FormatCSV:
+		return p.printDelimited(data, ',')
+	case FormatTSV:
+		return p.printDelimited(data, '\t')
+	case FormatTemplate:
+		return p.printTemplate(data)
+	default:
+		return p.printJSON(data)
+	}
+}
+
+func (p Printer) printJSON(data interface{}) error {
+	b, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(p.OutWriter, string(b))
+	return err
+}
+
+func (p Printer) printYAML(data interface{}) error {
+	b, err := yaml.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(p.OutWriter, string(b))
+	return err
+}
+
+// printDelimited flattens data into rows of the fields selected by
+// p.Fields (or all exported fields with a json tag, in struct-declared
+// order, when p.Fields is empty) and writes them as a delimited table
+// with a stable header row.
+func (p Printer) printDelimited(data interface{}, comma rune) error {
+	rows, header, err := flatten(data, p.Fields)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(p.OutWriter)
+	w.Comma = comma
+
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// printTable renders the same rows/header as printDelimited, as a
+// space-padded, human-readable table with one column per --fields
+// entry (or every field, in struct-declared order, when unset).
+func (p Printer) printTable(data interface{}) error {
+	rows, header, err := flatten(data, p.Fields)
+	if err != nil {
+		return err
+	}
+
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = len(h)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+
+	writeRow := func(cells []string) error {
+		padded := make([]string, len(cells))
+		for i, cell := range cells {
+			padded[i] = cell + strings.Repeat(" ", widths[i]-len(cell))
+		}
+		_, err := fmt.Fprintln(p.OutWriter, strings.TrimRight(strings.Join(padded, "  "), " "))
+		return err
+	}
+
+	if err := writeRow(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writeRow(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// printTemplate executes p.Template, a Go text/template, once per
+// element of data (or once, if data is not a slice).
+func (p Printer) printTemplate(data interface{}) error {
+	tmpl, err := template.New("pivnet-cli").Parse(p.Template)
+	if err != nil {
+		return fmt.Errorf("parsing --template: %s", err)
+	}
+
+	for _, elem := range elements(data) {
+		if err := tmpl.Execute(p.OutWriter, elem); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(p.OutWriter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// elements returns data as a slice of its elements, wrapping a single,
+// non-slice value in a one-element slice.
+func elements(data interface{}) []interface{} {
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Slice {
+		return []interface{}{data}
+	}
+
+	out := make([]interface{}, v.Len())
+	for i := range out {
+		out[i] = v.Index(i).Interface()
+	}
+	return out
+}
+
+// flatten turns data into a header row and a set of string rows, one
+// per element. Columns come from each struct field's json tag name,
+// filtered/reordered by fields (a comma-separated list) when non-empty.
+func flatten(data interface{}, fields string) (rows [][]string, header []string, err error) {
+	elems := elements(data)
+	if len(elems) == 0 {
+		return nil, nil, nil
+	}
+
+	allColumns := structColumns(reflect.TypeOf(elems[0]))
+
+	header = allColumns
+	if fields != "" {
+		header = strings.Split(fields, ",")
+		for i := range header {
+			header[i] = strings.TrimSpace(header[i])
+		}
+
+		for _, f := range header {
+			if !contains(allColumns, f) {
+				return nil, nil, fmt.Errorf("unknown --fields value %q", f)
+			}
+		}
+	}
+
+	for _, elem := range elems {
+		row, err := rowFor(elem, header)
+		if err != nil {
+			return nil, nil, err
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, header, nil
+}
+
+func structColumns(t reflect.Type) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var columns []string
+	for i := 0; i < t.NumField(); i++ {
+		if name, ok := jsonFieldName(t.Field(i)); ok {
+			columns = append(columns, name)
+		}
+	}
+	return columns
+}
+
+func rowFor(elem interface{}, fields []string) ([]string, error) {
+	v := reflect.ValueOf(elem)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	t := v.Type()
+
+	row := make([]string, len(fields))
+	for i, field := range fields {
+		found := false
+		for j := 0; j < t.NumField(); j++ {
+			name, ok := jsonFieldName(t.Field(j))
+			if !ok || name != field {
+				continue
+			}
+
+			row[i] = fmt.Sprintf("%v", v.Field(j).Interface())
+			found = true
+			break
+		}
+
+		if !found {
+			return nil, fmt.Errorf("unknown --fields value %q", field)
+		}
+	}
+
+	return row, nil
+}
+
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "" || tag == "-" || f.PkgPath != "" {
+		return "", false
+	}
+
+	name := strings.Split(tag, ",")[0]
+	if name == "" {
+		name = f.Name
+	}
+
+	return name, true
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
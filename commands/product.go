@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/pivotal-cf/go-pivnet"
+
+	"github.com/pivotal-cf/pivnet-cli/commands/printer"
+)
+
+// ProductCommand fetches and prints a single product by slug.
+type ProductCommand struct {
+	ProductSlug string `long:"product-slug" description:"Slug of the product" required:"true"`
+}
+
+// Execute authenticates as the current profile, fetches the product,
+// and prints it in the configured --format.
+func (command *ProductCommand) Execute([]string) error {
+	profile, err := currentProfile()
+	if err != nil {
+		return err
+	}
+
+	client := httpClientForProfile(profile)
+
+	if err := requireAuthentication(client, profile.Host); err != nil {
+		return err
+	}
+
+	resp, err := client.Get(profile.Host + apiPrefix + "/products/" + command.ProductSlug)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d fetching product %q", resp.StatusCode, command.ProductSlug)
+	}
+
+	var product pivnet.Product
+	if err := json.NewDecoder(resp.Body).Decode(&product); err != nil {
+		return err
+	}
+
+	p := printer.NewPrinter(os.Stdout)
+	p.Format = printer.Format(Pivnet.Format)
+	p.Fields = Pivnet.Fields
+	p.Template = Pivnet.Template
+
+	return p.PrintData(product)
+}
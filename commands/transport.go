@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/pivotal-cf/pivnet-cli/login"
+	"github.com/pivotal-cf/pivnet-cli/rc"
+)
+
+// apiTokenTransport authenticates every request with a static, legacy
+// API token pasted via `--api-token`.
+type apiTokenTransport struct {
+	apiToken string
+	base     http.RoundTripper
+}
+
+func (t *apiTokenTransport) roundTripper() http.RoundTripper {
+	if t.base != nil {
+		return t.base
+	}
+	return http.DefaultTransport
+}
+
+func (t *apiTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	authed := req.Clone(req.Context())
+	authed.Header.Set("Authorization", "Token "+t.apiToken)
+	return t.roundTripper().RoundTrip(authed)
+}
+
+// oidcTransport authenticates every request with a Bearer access token
+// obtained via the OIDC login flow. When a request comes back 401, it
+// transparently refreshes the access token using the stored refresh
+// token, persists the refreshed tokens, and retries the request once.
+type oidcTransport struct {
+	profile rc.Profile
+	base    http.RoundTripper
+}
+
+func (t *oidcTransport) roundTripper() http.RoundTripper {
+	if t.base != nil {
+		return t.base
+	}
+	return http.DefaultTransport
+}
+
+func (t *oidcTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	getBody, err := rewindableBody(req)
+	if err != nil {
+		return nil, fmt.Errorf("buffering request body: %s", err)
+	}
+
+	authed := req.Clone(req.Context())
+	authed.Header.Set("Authorization", "Bearer "+t.profile.AccessToken)
+
+	resp, err := t.roundTripper().RoundTrip(authed)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	resp.Body.Close()
+
+	tokens, err := login.Refresh(nil, t.profile.OIDCIssuer, t.profile.RefreshToken, OIDCClientID)
+	if err != nil {
+		return nil, fmt.Errorf("refreshing OIDC access token: %s", err)
+	}
+
+	t.profile.AccessToken = tokens.AccessToken
+	if tokens.RefreshToken != "" {
+		t.profile.RefreshToken = tokens.RefreshToken
+	}
+
+	if err := persistRefreshedProfile(t.profile); err != nil {
+		return nil, err
+	}
+
+	retry := req.Clone(req.Context())
+	retry.Header.Set("Authorization", "Bearer "+t.profile.AccessToken)
+	if getBody != nil {
+		retry.Body, err = getBody()
+		if err != nil {
+			return nil, fmt.Errorf("rewinding request body for retry: %s", err)
+		}
+	}
+	return t.roundTripper().RoundTrip(retry)
+}
+
+// rewindableBody ensures req.Body (if any) can be read more than once.
+// req.Clone shares the original Body by reference, so without this, the
+// first RoundTrip attempt consumes and closes it, leaving any retry (e.g.
+// after an OIDC token refresh) to send an empty body. If req already
+// carries a GetBody func (set by http.NewRequest for common body types),
+// it's reused as-is; otherwise the body is buffered into memory so a
+// fresh reader can be produced for each attempt.
+func rewindableBody(req *http.Request) (func() (io.ReadCloser, error), error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		return req.GetBody, nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(data))
+
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}, nil
+}
+
+// persistRefreshedProfile writes profile's refreshed OIDC tokens back
+// to wherever they came from: the OS keyring, if KeyringService is set,
+// or `.pivnetrc` directly otherwise.
+func persistRefreshedProfile(profile rc.Profile) error {
+	if profile.KeyringService != "" {
+		return credentialStore().Set(profile.KeyringService, profile.KeyringAccount, encodeOIDCSecret(profile))
+	}
+
+	rcContents, err := rc.LoadRC(Pivnet.ConfigFilepath)
+	if err != nil {
+		return err
+	}
+
+	rcContents.UpsertProfile(profile)
+	return rc.SaveRC(Pivnet.ConfigFilepath, rcContents)
+}
@@ -0,0 +1,87 @@
+package credentials
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+)
+
+// FileFake is a Store backed by a single JSON file instead of an OS
+// keyring. It exists for integration tests: each CLI invocation in the
+// integration suite is a separate process, so an in-process map can't
+// be shared between e.g. `login` and a later `product` command the way
+// a real keyring daemon is shared between them; a fake backed by a file
+// in the test's temp dir gives the same effectively-in-memory behaviour
+// for the lifetime of a single test.
+type FileFake struct {
+	Path string
+}
+
+func (f FileFake) entries() (map[string]string, error) {
+	entries := map[string]string{}
+
+	b, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (f FileFake) save(entries map[string]string) error {
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(f.Path, b, os.FileMode(0600))
+}
+
+func key(service, account string) string {
+	return service + "\x00" + account
+}
+
+// Set stores secret under service/account.
+func (f FileFake) Set(service, account, secret string) error {
+	entries, err := f.entries()
+	if err != nil {
+		return err
+	}
+
+	entries[key(service, account)] = secret
+	return f.save(entries)
+}
+
+// Get retrieves the secret stored under service/account.
+func (f FileFake) Get(service, account string) (string, error) {
+	entries, err := f.entries()
+	if err != nil {
+		return "", err
+	}
+
+	secret, ok := entries[key(service, account)]
+	if !ok {
+		return "", ErrNotFound{Service: service, Account: account}
+	}
+	return secret, nil
+}
+
+// Delete removes the secret stored under service/account.
+func (f FileFake) Delete(service, account string) error {
+	entries, err := f.entries()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := entries[key(service, account)]; !ok {
+		return ErrNotFound{Service: service, Account: account}
+	}
+
+	delete(entries, key(service, account))
+	return f.save(entries)
+}
@@ -0,0 +1,32 @@
+package credentials
+
+import (
+	"github.com/zalando/go-keyring"
+)
+
+// OSKeyring stores secrets in the platform keychain: Keychain on macOS,
+// Secret Service on Linux, Credential Manager on Windows.
+type OSKeyring struct{}
+
+// Set stores secret in the OS keyring, overwriting any existing value.
+func (OSKeyring) Set(service, account, secret string) error {
+	return keyring.Set(service, account, secret)
+}
+
+// Get retrieves the secret stored under service/account.
+func (OSKeyring) Get(service, account string) (string, error) {
+	secret, err := keyring.Get(service, account)
+	if err == keyring.ErrNotFound {
+		return "", ErrNotFound{Service: service, Account: account}
+	}
+	return secret, err
+}
+
+// Delete removes the secret stored under service/account.
+func (OSKeyring) Delete(service, account string) error {
+	err := keyring.Delete(service, account)
+	if err == keyring.ErrNotFound {
+		return ErrNotFound{Service: service, Account: account}
+	}
+	return err
+}
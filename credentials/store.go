@@ -0,0 +1,24 @@
+// Package credentials abstracts over where the pivnet CLI keeps secret
+// token material, so that it can live either in the OS keyring or
+// (by default) in the `.pivnetrc` profile itself.
+package credentials
+
+// Store sets, retrieves and deletes a single secret, identified by a
+// service/account pair, the same shape the OS keychain, Secret Service
+// and Credential Manager all expose.
+type Store interface {
+	Set(service, account, secret string) error
+	Get(service, account string) (string, error)
+	Delete(service, account string) error
+}
+
+// ErrNotFound is returned by Get/Delete when no secret is stored for the
+// given service/account.
+type ErrNotFound struct {
+	Service string
+	Account string
+}
+
+func (e ErrNotFound) Error() string {
+	return "no credential found for " + e.Service + "/" + e.Account
+}
@@ -0,0 +1,35 @@
+package login
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// suppressBrowserEnvVar, when set, makes defaultOpenBrowser a no-op
+// instead of shelling out; it is only ever set by the integration test
+// suite, which drives the OIDC callback directly and has no handler
+// registered for whatever the real browser would have requested.
+const suppressBrowserEnvVar = "PIVNET_CLI_SUPPRESS_BROWSER"
+
+// defaultOpenBrowser shells out to the platform-appropriate command to
+// open authURL in the user's default browser.
+func defaultOpenBrowser(authURL string) error {
+	if os.Getenv(suppressBrowserEnvVar) != "" {
+		return nil
+	}
+
+	var cmd string
+	var args []string
+
+	switch runtime.GOOS {
+	case "darwin":
+		cmd, args = "open", []string{authURL}
+	case "windows":
+		cmd, args = "rundll32", []string{"url.dll,FileProtocolHandler", authURL}
+	default:
+		cmd, args = "xdg-open", []string{authURL}
+	}
+
+	return exec.Command(cmd, args...).Start()
+}
@@ -0,0 +1,74 @@
+package login
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+)
+
+// jwk is a single entry from a JWKS document, restricted to the RSA
+// fields the pivnet OIDC providers are expected to use.
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// jwks is a parsed `jwks_uri` response.
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+func (k jwks) key(kid string) (*rsa.PublicKey, bool) {
+	for _, key := range k.Keys {
+		if key.Kid != kid || key.Kty != "RSA" {
+			continue
+		}
+
+		pub, err := key.rsaPublicKey()
+		if err != nil {
+			return nil, false
+		}
+		return pub, true
+	}
+	return nil, false
+}
+
+func (k jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("decoding jwk modulus: %s", err)
+	}
+
+	eBytes, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("decoding jwk exponent: %s", err)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(new(big.Int).SetBytes(eBytes).Int64()),
+	}, nil
+}
+
+func fetchJWKS(client *http.Client, jwksURI string) (jwks, error) {
+	resp, err := client.Get(jwksURI)
+	if err != nil {
+		return jwks{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return jwks{}, fmt.Errorf("unexpected status %d from jwks endpoint", resp.StatusCode)
+	}
+
+	var k jwks
+	if err := json.NewDecoder(resp.Body).Decode(&k); err != nil {
+		return jwks{}, err
+	}
+	return k, nil
+}
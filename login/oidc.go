@@ -0,0 +1,300 @@
+// Package login implements the browser-based OIDC authorization-code
+// flow used by `pivnet login --sso`, as an alternative to pasting a
+// static `--api-token`.
+package login
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// Discovery is the subset of an OIDC provider's
+// `/.well-known/openid-configuration` document that the CLI needs.
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// TokenResponse is the body returned from the token endpoint.
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	TokenType    string `json:"token_type"`
+}
+
+// OpenBrowser is the function used to launch the system browser; it is a
+// variable so tests can substitute a fake that drives the callback
+// directly instead of spawning a real browser.
+var OpenBrowser = defaultOpenBrowser
+
+// Flow drives a single OIDC authorization-code-with-PKCE login.
+type Flow struct {
+	Issuer     string
+	ClientID   string
+	HTTPClient *http.Client
+}
+
+// Result holds everything that needs to be persisted to `.pivnetrc`
+// after a successful login.
+type Result struct {
+	Issuer       string
+	AccessToken  string
+	RefreshToken string
+	IDToken      string
+	ExpiresAt    time.Time
+}
+
+// Run discovers the issuer's endpoints, starts a localhost callback
+// listener, opens the browser to the authorization endpoint, waits for
+// the redirect, exchanges the code, and validates the id_token. It
+// blocks until login completes, the context is cancelled, or the
+// callback times out.
+func (f Flow) Run(ctx context.Context) (*Result, error) {
+	client := f.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	discovery, err := fetchDiscovery(client, f.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("discovering OIDC configuration: %s", err)
+	}
+
+	state, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := randomString(64)
+	if err != nil {
+		return nil, err
+	}
+	challenge := codeChallengeS256(verifier)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("starting callback listener: %s", err)
+	}
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	redirectURI := fmt.Sprintf("http://127.0.0.1:%d/callback", port)
+
+	codeCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		if q.Get("state") != state {
+			errCh <- errors.New("state mismatch in OIDC callback")
+			http.Error(w, "state mismatch", http.StatusBadRequest)
+			return
+		}
+
+		if errStr := q.Get("error"); errStr != "" {
+			errCh <- fmt.Errorf("authorization server returned error: %s", errStr)
+			http.Error(w, errStr, http.StatusBadRequest)
+			return
+		}
+
+		code := q.Get("code")
+		if code == "" {
+			errCh <- errors.New("no code in OIDC callback")
+			http.Error(w, "missing code", http.StatusBadRequest)
+			return
+		}
+
+		fmt.Fprintln(w, "Login successful, you may close this window and return to the CLI.")
+		codeCh <- code
+	})
+
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	authURL := buildAuthorizationURL(discovery.AuthorizationEndpoint, f.ClientID, redirectURI, state, challenge)
+
+	fmt.Printf("Please visit the following URL to log in:\n\n  %s\n\n", authURL)
+	if err := OpenBrowser(authURL); err != nil {
+		fmt.Printf("Could not open a browser automatically: %s\n", err)
+	}
+
+	var code string
+	select {
+	case code = <-codeCh:
+	case err := <-errCh:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	tokens, err := exchangeCode(client, discovery.TokenEndpoint, f.ClientID, code, verifier, redirectURI)
+	if err != nil {
+		return nil, fmt.Errorf("exchanging code: %s", err)
+	}
+
+	if err := validateIDToken(client, discovery, tokens.IDToken, f.ClientID); err != nil {
+		return nil, fmt.Errorf("validating id_token: %s", err)
+	}
+
+	return &Result{
+		Issuer:       discovery.Issuer,
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		IDToken:      tokens.IDToken,
+		ExpiresAt:    time.Now().Add(time.Duration(tokens.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// Refresh exchanges a refresh token for a new token set, used by the
+// pivnet HTTP client when a request comes back 401.
+func Refresh(client *http.Client, issuer, refreshToken, clientID string) (*TokenResponse, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	discovery, err := fetchDiscovery(client, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	form.Set("client_id", clientID)
+
+	return postTokenForm(client, discovery.TokenEndpoint, form)
+}
+
+func fetchDiscovery(client *http.Client, issuer string) (*Discovery, error) {
+	resp, err := client.Get(issuer + "/.well-known/openid-configuration")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from discovery endpoint", resp.StatusCode)
+	}
+
+	var d Discovery
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func exchangeCode(client *http.Client, tokenEndpoint, clientID, code, verifier, redirectURI string) (*TokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+	form.Set("client_id", clientID)
+	form.Set("code_verifier", verifier)
+
+	return postTokenForm(client, tokenEndpoint, form)
+}
+
+func postTokenForm(client *http.Client, tokenEndpoint string, form url.Values) (*TokenResponse, error) {
+	resp, err := client.PostForm(tokenEndpoint, form)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from token endpoint", resp.StatusCode)
+	}
+
+	var t TokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// validateIDToken fetches the provider's JWKS and verifies the
+// id_token's signature and iss/aud/exp claims.
+func validateIDToken(client *http.Client, discovery *Discovery, idToken, clientID string) error {
+	keySet, err := fetchJWKS(client, discovery.JWKSURI)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := jwt.Parse(idToken, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keySet.key(kid)
+		if !ok {
+			return nil, fmt.Errorf("no matching key %q in jwks", kid)
+		}
+		return key, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok || !parsed.Valid {
+		return errors.New("invalid id_token")
+	}
+
+	if iss, _ := claims["iss"].(string); iss != discovery.Issuer {
+		return fmt.Errorf("id_token iss %q does not match issuer %q", iss, discovery.Issuer)
+	}
+
+	if !claims.VerifyAudience(clientID, true) {
+		return errors.New("id_token aud does not match client id")
+	}
+
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return errors.New("id_token is expired")
+	}
+
+	return nil
+}
+
+func buildAuthorizationURL(authEndpoint, clientID, redirectURI, state, codeChallenge string) string {
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", clientID)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("state", state)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+	q.Set("scope", "openid profile offline_access")
+
+	return authEndpoint + "?" + q.Encode()
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func codeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,134 @@
+// Package rc reads and writes the pivnet CLI's `.pivnetrc` config file,
+// which stores one or more named profiles so users can switch between
+// Pivotal Network hosts/credentials without re-authenticating every time.
+package rc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CredentialType distinguishes how a profile's credential material is
+// stored and refreshed.
+type CredentialType string
+
+const (
+	// CredentialTypeAPIToken is a long-lived static API token pasted by the
+	// user, stored as plaintext in the profile itself.
+	CredentialTypeAPIToken CredentialType = "api-token"
+
+	// CredentialTypeOIDC is an OIDC/OAuth2 token set obtained via the
+	// browser-based authorization-code flow.
+	CredentialTypeOIDC CredentialType = "oidc"
+
+	// CredentialTypeKeyring means the actual secret material lives in the
+	// OS keyring, under KeyringService/KeyringAccount; the profile itself
+	// holds no secrets and is safe to leave in plaintext yaml.
+	CredentialTypeKeyring CredentialType = "keyring"
+)
+
+// Profile is a single named set of host/credential information persisted
+// to `.pivnetrc`.
+type Profile struct {
+	Name              string `yaml:"name"`
+	APIToken          string `yaml:"api-token,omitempty"`
+	Host              string `yaml:"host"`
+	SkipSSLValidation bool   `yaml:"skip-ssl-validation,omitempty"`
+
+	CredentialType CredentialType `yaml:"credential-type,omitempty"`
+
+	// OIDC fields are only populated when CredentialType is
+	// CredentialTypeOIDC, except OIDCIssuer, which is also kept on a
+	// CredentialTypeKeyring profile whose underlying credential is OIDC.
+	OIDCIssuer        string `yaml:"oidc-issuer,omitempty"`
+	AccessToken       string `yaml:"access-token,omitempty"`
+	RefreshToken      string `yaml:"refresh-token,omitempty"`
+	IDToken           string `yaml:"id-token,omitempty"`
+	AccessTokenExpiry int64  `yaml:"access-token-expiry,omitempty"`
+
+	// Keyring fields are only populated when CredentialType is
+	// CredentialTypeKeyring. KeyringService/KeyringAccount are a
+	// non-secret reference into the OS keyring; no token material is
+	// stored in the profile itself. UnderlyingCredentialType records
+	// which kind of credential (api-token or oidc) was moved into the
+	// keyring, so it can be retrieved into the right shape.
+	KeyringService           string         `yaml:"keyring-service,omitempty"`
+	KeyringAccount           string         `yaml:"keyring-account,omitempty"`
+	UnderlyingCredentialType CredentialType `yaml:"underlying-credential-type,omitempty"`
+}
+
+// RC is the root document stored at `.pivnetrc`.
+type RC struct {
+	Profiles []Profile `yaml:"profiles"`
+}
+
+// LoadRC reads and parses the rc file at filepath. A missing file is not
+// an error; it is treated as an RC with no profiles.
+func LoadRC(filepath string) (*RC, error) {
+	if _, err := os.Stat(filepath); os.IsNotExist(err) {
+		return &RC{}, nil
+	}
+
+	b, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	var rc RC
+	if err := yaml.Unmarshal(b, &rc); err != nil {
+		return nil, err
+	}
+
+	return &rc, nil
+}
+
+// SaveRC writes rc to filepath as yaml, creating the file if necessary.
+func SaveRC(filepath string, rc *RC) error {
+	b, err := yaml.Marshal(rc)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath, b, os.FileMode(0600))
+}
+
+// ProfileForName returns the profile with the given name, or nil if no
+// such profile exists.
+func (rc *RC) ProfileForName(name string) *Profile {
+	for i := range rc.Profiles {
+		if rc.Profiles[i].Name == name {
+			return &rc.Profiles[i]
+		}
+	}
+	return nil
+}
+
+// UpsertProfile replaces the profile with the same name as p, or appends
+// p if no such profile exists.
+func (rc *RC) UpsertProfile(p Profile) {
+	for i := range rc.Profiles {
+		if rc.Profiles[i].Name == p.Name {
+			rc.Profiles[i] = p
+			return
+		}
+	}
+	rc.Profiles = append(rc.Profiles, p)
+}
+
+// RemoveProfile removes the named profile, if present.
+func (rc *RC) RemoveProfile(name string) {
+	for i := range rc.Profiles {
+		if rc.Profiles[i].Name == name {
+			rc.Profiles = append(rc.Profiles[:i], rc.Profiles[i+1:]...)
+			return
+		}
+	}
+}
+
+// ErrProfileNotFound is returned when a named profile cannot be located.
+func ErrProfileNotFound(name string) error {
+	return fmt.Errorf("profile %q not found", name)
+}